@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -53,10 +55,60 @@ type Converter struct {
 	EscapeSpecialChars  bool
 	EnableFrontMatter   bool
 	FrontMatterTemplate string
+	// FrontMatterFormat selects a named front matter preset. When it is
+	// FrontMatterCustom (the default), FrontMatterTemplate is rendered as-is.
+	FrontMatterFormat FrontMatterFormat
+
+	// LinkResolver rewrites intra-notebook Evernote links to point at the
+	// corresponding converted note. It is nil unless the caller has run the
+	// indexing pass described on LinkResolver and assigned it here.
+	LinkResolver *LinkResolver
+
+	// Layout decides where a converted note is written to. It defaults to
+	// nil, in which case callers fall back to their own flat/notebook
+	// behavior; set it to route notes through FlatLayout, NotebookLayout,
+	// JekyllLayout or HugoContentLayout instead.
+	Layout Layout
+
+	// ResourceNaming selects how attached resources (images and files) are
+	// named in the output. It defaults to the zero value, which behaves like
+	// ResourceNamingSequential.
+	ResourceNaming ResourceNaming
+	// ResourceHashLength is the number of hex characters kept from the
+	// content hash when ResourceNaming is ResourceNamingContentHash. It
+	// defaults to 12 when zero or negative.
+	ResourceHashLength int
+
+	// Timezone is applied to a note's dates before they are formatted. It
+	// defaults to nil, which behaves like time.UTC and matches historical
+	// behavior.
+	Timezone *time.Location
+	// DateFormat is the Go time layout used when formatting the front
+	// matter's date fields. It defaults to dateFrontMatterFormat when empty.
+	// JekyllLayout's filename and permalink always use YYYY-MM-DD.
+	DateFormat string
+	// FallbackTime is used in place of a note's Created/Updated date when it
+	// fails to parse. Callers should set this to the source ENEX file's
+	// mtime before calling Convert; it defaults to time.Now() when zero.
+	FallbackTime time.Time
+	// TimezoneFromLocation opts into deriving a note's timezone from the
+	// latitude/longitude recorded in its Evernote attributes, instead of
+	// Timezone, when the note has those attributes set.
+	TimezoneFromLocation bool
+
+	// MarkdownFlavor selects the Renderer used by toMarkdown. It defaults to
+	// the zero value, which behaves like MarkdownCommonMark.
+	MarkdownFlavor MarkdownFlavor
 
 	// err holds an error during conversion
 	// Every conversion step should check this field and skip execution if it is not empty
 	err error
+
+	// usedResourceNames tracks output filenames already handed out by
+	// resourceName in ResourceNamingOriginal mode, across every note
+	// processed by this Converter, so that two different notes attaching a
+	// resource with the same original filename don't clobber each other.
+	usedResourceNames map[string]bool
 }
 
 // NewConverter creates a Converter with valid tagTemplate
@@ -75,17 +127,38 @@ func NewConverter(tagTemplate string, enableFrontMatter, enableHighlights, escap
 		EscapeSpecialChars:  escapeSpecialChars,
 		EnableFrontMatter:   enableFrontMatter,
 		FrontMatterTemplate: FrontMatterTemplate,
+		FrontMatterFormat:   FrontMatterCustom,
 	}, nil
 }
 
+// LoadFrontMatterTemplate reads a user-supplied front matter template from disk
+// and configures the Converter to use it in FrontMatterCustom mode. It is the
+// counterpart of the CLI's --front-matter-template flag.
+func (c *Converter) LoadFrontMatterTemplate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read front matter template: %w", err)
+	}
+
+	c.FrontMatterTemplate = string(data)
+	c.FrontMatterFormat = FrontMatterCustom
+
+	return nil
+}
+
 // Convert an Evernote file to markdown
 func (c *Converter) Convert(note *enex.Note, cnt int) (*markdown.Note, error) {
 	md := new(markdown.Note)
 	md.Media = map[string]markdown.Resource{}
 
 	c.mapResources(note, md, cnt)
-	c.normalizeHTML(note, md, NewReplacerMedia(md.Media), &Code{}, &ExtraDiv{}, &TextFormatter{}, &EmptyAnchor{}, &NormalizeTodo{})
+	replacers := []Replacer{NewReplacerMedia(md.Media), &Code{}, &ExtraDiv{}, &TextFormatter{}, &EmptyAnchor{}, &NormalizeTodo{}}
+	if c.LinkResolver != nil {
+		replacers = append(replacers, NewReplacerLinks(c.LinkResolver))
+	}
+	c.normalizeHTML(note, md, replacers...)
 	c.toMarkdown(note, md)
+	c.resolveTitleLinks(note, md)
 	c.prependTags(note, md)
 	c.prependTitle(note, md)
 	c.trimSpaces(note, md)
@@ -121,7 +194,7 @@ func (c *Converter) mapResources(note *enex.Note, md *markdown.Note, cntp int) {
 		}
 
 		mdr := markdown.Resource{
-			Name:    strconv.Itoa(cntp) + "_" + strconv.Itoa(i) + ext,
+			Name:    c.resourceName(p, name, ext, cntp, i),
 			Type:    rType,
 			Content: p,
 		}
@@ -136,6 +209,75 @@ func (c *Converter) mapResources(note *enex.Note, md *markdown.Note, cntp int) {
 	}
 }
 
+// ResourceNaming selects how Converter.mapResources names attached resources
+// in the output.
+type ResourceNaming string
+
+const (
+	// ResourceNamingSequential names resources "<note index>_<resource index><ext>",
+	// matching the historical behavior. It is stable only as long as the
+	// input ordering doesn't change.
+	ResourceNamingSequential ResourceNaming = "sequential"
+	// ResourceNamingContentHash names resources after a hash of their
+	// content, so re-running the conversion on unchanged resources always
+	// produces the same filename.
+	ResourceNamingContentHash ResourceNaming = "contenthash"
+	// ResourceNamingOriginal reuses the resource's original filename from
+	// the ENEX file, deduplicated when two resources share a name.
+	ResourceNamingOriginal ResourceNaming = "original"
+)
+
+const defaultResourceHashLength = 12
+
+// resourceName picks the output filename for one resource according to
+// c.ResourceNaming. dedupedName and ext are the original-filename-derived
+// name already deduplicated against sibling resources; cntp and i are the
+// note and resource indexes used by ResourceNamingSequential.
+func (c *Converter) resourceName(content []byte, dedupedName, ext string, cntp, i int) string {
+	switch c.ResourceNaming {
+	case ResourceNamingContentHash:
+		length := c.ResourceHashLength
+		if length <= 0 {
+			length = defaultResourceHashLength
+		}
+		// Reuse the same MD5 sum used as the media map key, so the map
+		// lookup and the on-disk filename always agree.
+		hash := fmt.Sprintf("%x", md5.Sum(content))
+		if length < len(hash) {
+			hash = hash[:length]
+		}
+
+		return hash + ext
+	case ResourceNamingOriginal:
+		return c.dedupeAcrossExport(dedupedName + ext)
+	case ResourceNamingSequential:
+		fallthrough
+	default:
+		return strconv.Itoa(cntp) + "_" + strconv.Itoa(i) + ext
+	}
+}
+
+// dedupeAcrossExport returns name unchanged the first time it's seen across
+// the whole Converter's lifetime, and otherwise appends an incrementing
+// "-<n>" suffix (before the extension) until it finds one that hasn't been
+// used yet. mapResources' own names map only dedupes within a single note;
+// this catches the same original filename recurring in a different note.
+func (c *Converter) dedupeAcrossExport(name string) string {
+	if c.usedResourceNames == nil {
+		c.usedResourceNames = map[string]bool{}
+	}
+
+	candidate := name
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for n := 1; c.usedResourceNames[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+
+	c.usedResourceNames[candidate] = true
+	return candidate
+}
+
 func (c *Converter) prependTitle(note *enex.Note, md *markdown.Note) {
 	if c.err != nil {
 		return
@@ -149,7 +291,7 @@ func (c *Converter) toMarkdown(note *enex.Note, md *markdown.Note) {
 		return
 	}
 	var b bytes.Buffer
-	err := markdown.Convert(&b, bytes.NewReader(note.Content), c.EnableHighlights, c.EscapeSpecialChars)
+	err := c.rendererFor().Render(&b, bytes.NewReader(note.Content))
 	if c.err = err; err != nil {
 		return
 	}
@@ -171,25 +313,61 @@ func (c *Converter) addDates(note *enex.Note, md *markdown.Note) {
 		return
 	}
 
-	md.CTime = convertEvernoteDate(note.Created)
-	md.MTime = convertEvernoteDate(note.Updated)
+	tz := c.Timezone
+	if c.TimezoneFromLocation {
+		if locTZ := timezoneFromAttributes(note.Attributes); locTZ != nil {
+			tz = locTZ
+		}
+	}
+
+	md.CTime = c.convertEvernoteDateIn(note.Created, tz)
+	md.MTime = c.convertEvernoteDateIn(note.Updated, tz)
+}
+
+// timezoneFromAttributes derives a fixed-offset *time.Location from a note's
+// recorded longitude, approximating one hour of offset per 15 degrees. This
+// is a rough opt-in approximation, not a lookup against a real tz database,
+// since Evernote attributes don't carry a timezone name.
+func timezoneFromAttributes(attrs enex.NoteAttributes) *time.Location {
+	if attrs.Longitude == 0 {
+		return nil
+	}
+
+	offsetHours := int(attrs.Longitude / 15)
+	return time.FixedZone(fmt.Sprintf("UTC%+d", offsetHours), offsetHours*3600)
 }
 
 const dateFrontMatterFormat = "2006-01-02 15:04:05 -0700"
 
+// dateFormat returns the Go time layout used for formatting dates, falling
+// back to dateFrontMatterFormat when the Converter doesn't specify one.
+func (c *Converter) dateFormat() string {
+	if c.DateFormat == "" {
+		return dateFrontMatterFormat
+	}
+
+	return c.DateFormat
+}
+
 func (c *Converter) addFrontMatter(note *enex.Note, md *markdown.Note) {
+	categories, permalink := c.layoutFrontMatter(note, md.CTime)
+
 	data := struct {
 		CTime      string
 		MTime      string
 		Title      string
 		Attributes enex.NoteAttributes
 		TagList    string
+		Categories string
+		Permalink  string
 	}{
-		md.CTime.Format(dateFrontMatterFormat),
-		md.MTime.Format(dateFrontMatterFormat),
+		md.CTime.Format(c.dateFormat()),
+		md.MTime.Format(c.dateFormat()),
 		note.Title,
 		note.Attributes,
 		c.tagList(note, "'{{tag}}'", ", ", false),
+		categories,
+		permalink,
 	}
 	tmpl, err := template.New("frontMatter").Funcs(template.FuncMap{
 		"trim": func(text string) string {
@@ -198,7 +376,7 @@ func (c *Converter) addFrontMatter(note *enex.Note, md *markdown.Note) {
 		"quote": func(text string) string {
 			return fmt.Sprintf("%q", text)
 		},
-	}).Parse(c.FrontMatterTemplate)
+	}).Parse(c.frontMatterTemplate())
 	if err != nil {
 		panic(err)
 	}
@@ -210,9 +388,44 @@ func (c *Converter) addFrontMatter(note *enex.Note, md *markdown.Note) {
 	md.Content = append(b.Bytes(), md.Content...)
 }
 
+// layoutFrontMatter derives the Categories and Permalink front matter
+// fields from c.Layout, so that a JekyllLayout's draft-filtered categories
+// and output path agree with what addFrontMatter renders. createdAt is
+// md.CTime - the same Timezone/FallbackTime-resolved date addFrontMatter
+// formats into the "date:" field - passed through to Path so the filename
+// and the front matter can't disagree, and so a parse failure only falls
+// back to time.Now() once rather than once per Path call. Both return
+// values are empty for any other Layout (including nil).
+func (c *Converter) layoutFrontMatter(note *enex.Note, createdAt time.Time) (categories, permalink string) {
+	var jekyll JekyllLayout
+	switch l := c.Layout.(type) {
+	case JekyllLayout:
+		jekyll = l
+	case *JekyllLayout:
+		jekyll = *l
+	default:
+		return "", ""
+	}
+
+	tags := jekyll.Categories(note)
+	quoted := make([]string, len(tags))
+	for i, tag := range tags {
+		quoted[i] = fmt.Sprintf("'%s'", tag)
+	}
+	categories = strings.Join(quoted, ", ")
+
+	permalink = "/" + strings.TrimSuffix(jekyll.Path(note, "", createdAt), ".md") + "/"
+
+	return categories, permalink
+}
+
 const evernoteDateFormat = "20060102T150405Z"
 
 // 20180109T173725Z -> 2018-01-09T17:37:25Z
+//
+// convertEvernoteDate parses in UTC, with no timezone conversion or
+// mtime-fallback behavior, falling back to time.Now() on parse failure.
+// Converter itself uses convertEvernoteDateIn instead.
 func convertEvernoteDate(evernoteDate string) time.Time {
 	converted, err := time.Parse(evernoteDateFormat, evernoteDate)
 	if err != nil {
@@ -222,3 +435,26 @@ func convertEvernoteDate(evernoteDate string) time.Time {
 
 	return converted
 }
+
+// convertEvernoteDateIn parses evernoteDate like the package-level
+// convertEvernoteDate, but additionally applies tz (when non-nil) and falls
+// back to c.FallbackTime (typically the source ENEX file's mtime) instead of
+// time.Now() when parsing fails, logging a warning rather than a debug
+// message since a fallback to file mtime usually indicates bad input data.
+func (c *Converter) convertEvernoteDateIn(evernoteDate string, tz *time.Location) time.Time {
+	converted, err := time.Parse(evernoteDateFormat, evernoteDate)
+	if err != nil {
+		fallback := c.FallbackTime
+		if fallback.IsZero() {
+			fallback = time.Now()
+		}
+		log.Printf("[WARN] Could not convert time %q: %s, falling back to %s", evernoteDate, err.Error(), fallback)
+		converted = fallback
+	}
+
+	if tz != nil {
+		return converted.In(tz)
+	}
+
+	return converted
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConverter_ResourceNaming(t *testing.T) {
+	t.Run("original mode dedupes across the whole export, not just one note", func(t *testing.T) {
+		c := &Converter{ResourceNaming: ResourceNamingOriginal}
+
+		// Two different notes (cntp 0 and 1) each attach a resource that
+		// happens to share the same original filename, e.g. "Screenshot.png"
+		// from two different notes.
+		first := c.resourceName([]byte("note one content"), "Screenshot", ".png", 0, 0)
+		second := c.resourceName([]byte("note two content"), "Screenshot", ".png", 1, 0)
+
+		if first == second {
+			t.Fatalf("expected distinct filenames across notes, got %q twice", first)
+		}
+		if first != "Screenshot.png" {
+			t.Fatalf("expected the first occurrence to keep the original name, got %q", first)
+		}
+		if second != "Screenshot-1.png" {
+			t.Fatalf("expected the second occurrence to get a numeric suffix, got %q", second)
+		}
+	})
+
+	t.Run("contenthash mode is stable across repeated runs", func(t *testing.T) {
+		c := &Converter{ResourceNaming: ResourceNamingContentHash}
+		content := []byte("identical content")
+
+		first := c.resourceName(content, "irrelevant", ".png", 0, 0)
+		second := c.resourceName(content, "irrelevant", ".png", 5, 2)
+
+		if first != second {
+			t.Fatalf("expected the same content to hash to the same name regardless of note/resource index, got %q and %q", first, second)
+		}
+	})
+
+	t.Run("sequential mode is the default", func(t *testing.T) {
+		c := &Converter{}
+		if got := c.resourceName([]byte("x"), "name", ".png", 3, 1); got != "3_1.png" {
+			t.Fatalf("got %q, want 3_1.png", got)
+		}
+	})
+}
+
+func TestConverter_convertEvernoteDateIn(t *testing.T) {
+	t.Run("applies the given timezone", func(t *testing.T) {
+		c := &Converter{}
+		est, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Skipf("tzdata not available: %s", err)
+		}
+
+		got := c.convertEvernoteDateIn("20240101T000000Z", est)
+		if got.Location() != est {
+			t.Fatalf("expected the date to be converted into %v, got %v", est, got.Location())
+		}
+	})
+
+	t.Run("falls back to FallbackTime, not time.Now, on parse failure", func(t *testing.T) {
+		fallback := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		c := &Converter{FallbackTime: fallback}
+
+		got := c.convertEvernoteDateIn("not-a-date", nil)
+		if !got.Equal(fallback) {
+			t.Fatalf("got %v, want fallback %v", got, fallback)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package internal
+
+// FrontMatterFormat selects which front matter preset a Converter renders.
+// "custom" keeps the historical behavior of rendering Converter.FrontMatterTemplate
+// as-is, while the named presets target the conventions of a specific static
+// site generator or note-taking app.
+type FrontMatterFormat string
+
+const (
+	FrontMatterHugo     FrontMatterFormat = "hugo"
+	FrontMatterJekyll   FrontMatterFormat = "jekyll"
+	FrontMatterZola     FrontMatterFormat = "zola"
+	FrontMatterObsidian FrontMatterFormat = "obsidian"
+	FrontMatterCustom   FrontMatterFormat = "custom"
+)
+
+// frontMatterTemplates maps a known FrontMatterFormat to the template used to
+// render it. FrontMatterCustom is intentionally absent here: it is resolved to
+// Converter.FrontMatterTemplate instead, since that's the field users already
+// populate for a custom preset.
+var frontMatterTemplates = map[FrontMatterFormat]string{
+	FrontMatterHugo: `---
+date: '{{.CTime}}'
+lastmod: '{{.MTime}}'
+title: {{ trim .Title | quote }}
+draft: false
+{{- if .TagList }}
+categories: [ {{ .TagList }} ]
+{{- end }}
+{{- with .Attributes -}}
+{{- if .SourceUrl }}
+url: {{ trim .SourceUrl -}}
+{{- end }}
+{{- end }}
+
+---
+
+`,
+	FrontMatterJekyll: `---
+layout: post
+date: '{{.CTime}}'
+title: {{ trim .Title | quote }}
+{{- if .Categories }}
+categories: [ {{ .Categories }} ]
+{{- end }}
+{{- if .TagList }}
+tags: [ {{ .TagList }} ]
+{{- end }}
+{{- if .Permalink }}
+permalink: {{ .Permalink }}
+{{- end }}
+{{- with .Attributes -}}
+{{- if .SourceUrl }}
+url: {{ trim .SourceUrl -}}
+{{- end }}
+{{- end }}
+
+---
+
+`,
+	FrontMatterZola: `+++
+date = '{{.CTime}}'
+updated = '{{.MTime}}'
+title = {{ trim .Title | quote }}
+{{- if .TagList }}
+[taxonomies]
+tags = [ {{ .TagList }} ]
+{{- end }}
+
++++
+
+`,
+	FrontMatterObsidian: `---
+created: '{{.CTime}}'
+updated: '{{.MTime}}'
+aliases: [ {{ trim .Title | quote }} ]
+{{- if .TagList }}
+tags: [ {{ .TagList }} ]
+{{- end }}
+
+---
+
+`,
+}
+
+// frontMatterTemplate resolves the template to render for the Converter's
+// configured FrontMatterFormat, falling back to FrontMatterTemplate for the
+// zero value and for FrontMatterCustom.
+func (c *Converter) frontMatterTemplate() string {
+	if c.FrontMatterFormat == "" || c.FrontMatterFormat == FrontMatterCustom {
+		return c.FrontMatterTemplate
+	}
+
+	if tmpl, ok := frontMatterTemplates[c.FrontMatterFormat]; ok {
+		return tmpl
+	}
+
+	return c.FrontMatterTemplate
+}
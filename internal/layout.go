@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wormi4ok/evernote2md/encoding/enex"
+)
+
+// Layout decides where a converted note is written to, relative to the
+// export's output root. Different generators expect different directory and
+// filename conventions, so the writer asks a Layout for the path instead of
+// hard-coding one.
+type Layout interface {
+	// Path returns the output path for note, relative to the output root,
+	// including the .md extension. notebook is the name of the note's
+	// containing notebook, as read from the ENEX file name. createdAt is the
+	// note's creation date, already converted through the Converter's
+	// configured Timezone/FallbackTime (see Converter.addDates): callers pass
+	// the same value used to compute front matter dates, so a date-dependent
+	// Layout like JekyllLayout can't disagree with the front matter it's
+	// paired with.
+	Path(note *enex.Note, notebook string, createdAt time.Time) string
+}
+
+// FlatLayout writes every note directly into the output root, named after
+// its title.
+type FlatLayout struct{}
+
+// Path implements Layout.
+func (FlatLayout) Path(note *enex.Note, _ string, _ time.Time) string {
+	return slugify(note.Title) + ".md"
+}
+
+// NotebookLayout groups notes into a subdirectory per notebook, matching the
+// default behavior of the CLI's multi-notebook mode.
+type NotebookLayout struct{}
+
+// Path implements Layout.
+func (NotebookLayout) Path(note *enex.Note, notebook string, _ time.Time) string {
+	return path.Join(notebook, slugify(note.Title)+".md")
+}
+
+// JekyllLayout places notes under _posts/ (or _drafts/ when the note is
+// tagged "draft" and Drafts is enabled), named YYYY-MM-DD-slug.md per
+// Jekyll's post convention. Tags other than "draft" are left for the
+// Converter to emit as front matter.
+type JekyllLayout struct {
+	// Drafts routes notes tagged "draft" into _drafts/ instead of _posts/,
+	// and omits the date prefix, matching Jekyll's draft convention.
+	Drafts bool
+}
+
+// Path implements Layout. createdAt must already be in the same timezone
+// used for the note's front matter date, so the _posts/ filename and the
+// front matter's date/permalink never disagree near local midnight.
+func (l JekyllLayout) Path(note *enex.Note, _ string, createdAt time.Time) string {
+	if l.Drafts && hasTag(note, "draft") {
+		return path.Join("_drafts", slugify(note.Title)+".md")
+	}
+
+	name := fmt.Sprintf("%s-%s.md", createdAt.Format("2006-01-02"), slugify(note.Title))
+
+	return path.Join("_posts", name)
+}
+
+// Categories returns the note's tags that should be mapped to Jekyll
+// categories, i.e. every tag except "draft", which JekyllLayout consumes
+// itself to route notes into _drafts/.
+func (l JekyllLayout) Categories(note *enex.Note) []string {
+	var categories []string
+	for _, tag := range note.Tags {
+		if strings.EqualFold(tag, "draft") {
+			continue
+		}
+		categories = append(categories, tag)
+	}
+
+	return categories
+}
+
+// HugoContentLayout places notes under content/<notebook>/slug.md, matching
+// Hugo's page-bundle-free content layout.
+type HugoContentLayout struct{}
+
+// Path implements Layout.
+func (HugoContentLayout) Path(note *enex.Note, notebook string, _ time.Time) string {
+	return path.Join("content", notebook, slugify(note.Title)+".md")
+}
+
+func hasTag(note *enex.Note, tag string) bool {
+	for _, t := range note.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify converts a note title into a filesystem- and URL-safe slug:
+// lowercased, non-alphanumeric runs collapsed to a single hyphen, and
+// leading/trailing hyphens trimmed.
+func slugify(title string) string {
+	slug := slugInvalidChars.ReplaceAllString(strings.ToLower(title), "-")
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		slug = "untitled"
+	}
+
+	return slug
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wormi4ok/evernote2md/encoding/enex"
+)
+
+func TestJekyllLayout_Path(t *testing.T) {
+	createdAt := time.Date(2024, 3, 15, 23, 30, 0, 0, time.UTC)
+
+	t.Run("dated post uses the given createdAt, not note.Created directly", func(t *testing.T) {
+		note := &enex.Note{Title: "Hello World", Created: "20240116T000000Z"}
+		layout := JekyllLayout{}
+
+		got := layout.Path(note, "", createdAt)
+		want := "_posts/2024-03-15-hello-world.md"
+		if got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("draft tagged note routes to _drafts without a date prefix", func(t *testing.T) {
+		note := &enex.Note{Title: "Work In Progress", Tags: []string{"draft"}}
+		layout := JekyllLayout{Drafts: true}
+
+		got := layout.Path(note, "", createdAt)
+		want := "_drafts/work-in-progress.md"
+		if got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("Categories excludes the draft tag", func(t *testing.T) {
+		note := &enex.Note{Tags: []string{"draft", "golang", "testing"}}
+		layout := JekyllLayout{}
+
+		got := layout.Categories(note)
+		if len(got) != 2 || got[0] != "golang" || got[1] != "testing" {
+			t.Fatalf("got %v, want [golang testing]", got)
+		}
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Hello World":     "hello-world",
+		"Already-Slugged": "already-slugged",
+		"  Spaces  ":      "spaces",
+		"":                "untitled",
+	}
+
+	for in, want := range cases {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
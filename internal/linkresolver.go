@@ -0,0 +1,283 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wormi4ok/evernote2md/encoding/enex"
+	"github.com/wormi4ok/evernote2md/encoding/markdown"
+)
+
+// LinkFormat selects how a resolved link is rendered in the output Markdown.
+type LinkFormat string
+
+const (
+	// LinkFormatWiki renders resolved links as Obsidian-style [[filename]] links.
+	LinkFormatWiki LinkFormat = "wiki"
+	// LinkFormatMarkdown renders resolved links as standard [title](path) links.
+	LinkFormatMarkdown LinkFormat = "markdown"
+	// LinkFormatTemplate renders resolved links using LinkResolver.Template.
+	LinkFormatTemplate LinkFormat = "template"
+)
+
+// DefaultLinkTemplate is used when LinkFormat is LinkFormatTemplate and no
+// template has been set explicitly.
+const DefaultLinkTemplate = "[{{.Title}}]({{.RelPath}})"
+
+// NoteRef identifies a single converted note for the purposes of link
+// resolution.
+type NoteRef struct {
+	// GUID is the Evernote note GUID, as found in <en-export> or a
+	// evernote:///view/.../<guid>/<guid>/ URL.
+	GUID string
+	// Title is the note's original title.
+	Title string
+	// Filename is the output file's base name, without directory.
+	Filename string
+	// RelPath is the output file's path relative to the export root.
+	RelPath string
+	// AbsPath is the output file's absolute path on disk.
+	AbsPath string
+}
+
+// LinkResolver indexes the notes produced by a conversion run by GUID and
+// normalized title so that intra-notebook links can be rewritten to point at
+// the corresponding output file. A resolver must be populated via Index
+// before normalizeHTML can resolve links against it.
+type LinkResolver struct {
+	// Format controls how a resolved link is rendered.
+	Format LinkFormat
+	// Template is used when Format is LinkFormatTemplate. It supports the
+	// variables {{.Filename}}, {{.RelPath}}, {{.AbsPath}}, {{.Title}} and
+	// {{.GUID}}.
+	Template string
+
+	byGUID  map[string]NoteRef
+	byTitle map[string]NoteRef
+
+	// Unresolved records links that could not be matched to any indexed
+	// note, each as the raw link target (URL or title) that was looked up.
+	Unresolved []string
+}
+
+// BuildNoteIndex runs the first of the two conversion passes described on
+// LinkResolver: it walks every note in the export, determines the GUID and
+// output path it will be written to via indexOf, and indexes it by GUID and
+// title. The returned resolver is ready to be assigned to Converter.LinkResolver
+// before the second pass (the actual Convert calls) runs.
+func BuildNoteIndex(notes []*enex.Note, format LinkFormat, indexOf func(*enex.Note) NoteRef) *LinkResolver {
+	resolver := NewLinkResolver(format)
+
+	for _, note := range notes {
+		resolver.Index(indexOf(note))
+	}
+
+	return resolver
+}
+
+// NewLinkResolver creates a LinkResolver rendering links in the given format.
+func NewLinkResolver(format LinkFormat) *LinkResolver {
+	return &LinkResolver{
+		Format:  format,
+		byGUID:  map[string]NoteRef{},
+		byTitle: map[string]NoteRef{},
+	}
+}
+
+// Index adds a note to the resolver's lookup tables. Convert callers are
+// expected to run a first pass over all notes, calling Index for each one,
+// before running the second pass that rewrites links.
+func (r *LinkResolver) Index(ref NoteRef) {
+	if ref.GUID != "" {
+		r.byGUID[ref.GUID] = ref
+	}
+	r.byTitle[normalizeTitle(ref.Title)] = ref
+}
+
+var evernoteLinkPattern = regexp.MustCompile(`evernote:///view/[^/]+/[^/]+/([a-f0-9-]+)/([a-f0-9-]+)/?`)
+
+// ResolveLink looks up a raw evernote:///view/... URL (as passed in by
+// ReplacerLinks) by GUID only. A link to a note outside the indexed set (a
+// different notebook, or one that was skipped) has no title to fall back to
+// - fuzzy-matching the URL itself against indexed titles would match on
+// coincidental substrings of the URL rather than of a real title, so GUID
+// lookup is the only strategy used here. If nothing matches, ok is false and
+// the target is recorded in Unresolved.
+func (r *LinkResolver) ResolveLink(target string) (resolved string, ok bool) {
+	if ref, found := r.byGUIDFromTarget(target); found {
+		return r.render(ref), true
+	}
+
+	r.Unresolved = append(r.Unresolved, target)
+	return "", false
+}
+
+// ResolveTitle looks up a bare note title (as passed in by resolveTitleLinks
+// for a "[[Title]]" in-note reference) and returns the link rendered in the
+// resolver's Format. The lookup order is an exact normalized title match, then a
+// fuzzy substring match against indexed titles. The fuzzy match only
+// succeeds when exactly one indexed title matches, so the result doesn't
+// depend on Go's randomized map iteration order; an ambiguous match is
+// treated the same as no match. If nothing matches, ok is false and the
+// target is recorded in Unresolved.
+func (r *LinkResolver) ResolveTitle(target string) (resolved string, ok bool) {
+	if ref, found := r.byTitle[normalizeTitle(target)]; found {
+		return r.render(ref), true
+	}
+
+	if ref, found := r.fuzzyTitleMatch(target); found {
+		return r.render(ref), true
+	}
+
+	r.Unresolved = append(r.Unresolved, target)
+	return "", false
+}
+
+func (r *LinkResolver) byGUIDFromTarget(target string) (NoteRef, bool) {
+	m := evernoteLinkPattern.FindStringSubmatch(target)
+	if m == nil {
+		return NoteRef{}, false
+	}
+
+	ref, ok := r.byGUID[m[2]]
+	return ref, ok
+}
+
+// fuzzyTitleMatch finds indexed titles that are a substring match (in either
+// direction) of target. To stay deterministic regardless of map iteration
+// order, it gathers every match and only returns a result when there's
+// exactly one distinct matching title - an ambiguous target (e.g. "view"
+// matching both "Code Review" and "Overview") is reported as unresolved
+// rather than picking an arbitrary winner.
+func (r *LinkResolver) fuzzyTitleMatch(target string) (NoteRef, bool) {
+	needle := normalizeTitle(target)
+	if needle == "" {
+		return NoteRef{}, false
+	}
+
+	var matchedTitles []string
+	for title := range r.byTitle {
+		if strings.Contains(title, needle) || strings.Contains(needle, title) {
+			matchedTitles = append(matchedTitles, title)
+		}
+	}
+
+	if len(matchedTitles) != 1 {
+		return NoteRef{}, false
+	}
+
+	return r.byTitle[matchedTitles[0]], true
+}
+
+func (r *LinkResolver) render(ref NoteRef) string {
+	switch r.Format {
+	case LinkFormatWiki:
+		return fmt.Sprintf("[[%s]]", ref.Filename)
+	case LinkFormatTemplate:
+		tmpl := r.Template
+		if tmpl == "" {
+			tmpl = DefaultLinkTemplate
+		}
+		replacer := strings.NewReplacer(
+			"{{.Filename}}", ref.Filename,
+			"{{.RelPath}}", ref.RelPath,
+			"{{.AbsPath}}", ref.AbsPath,
+			"{{.Title}}", ref.Title,
+			"{{.GUID}}", ref.GUID,
+		)
+		return replacer.Replace(tmpl)
+	case LinkFormatMarkdown:
+		fallthrough
+	default:
+		return fmt.Sprintf("[%s](%s)", ref.Title, ref.RelPath)
+	}
+}
+
+// Report returns a human-readable summary of links that could not be
+// resolved, suitable for printing to the user after a conversion run.
+func (r *LinkResolver) Report() string {
+	if len(r.Unresolved) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d link(s) could not be resolved:\n", len(r.Unresolved))
+	for _, target := range r.Unresolved {
+		fmt.Fprintf(&b, "  - %s\n", target)
+	}
+
+	return b.String()
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// ReplacerLinks rewrites <a href="evernote:///view/...">  links to point at
+// the corresponding converted note, using the given LinkResolver. It follows
+// the same Replacer shape as ReplacerMedia, so it can be passed alongside the
+// other normalizeHTML replacers. Bare-title "[[Title]]" references are
+// handled separately by Converter.resolveTitleLinks, since those aren't HTML
+// tags.
+type ReplacerLinks struct {
+	resolver *LinkResolver
+}
+
+// NewReplacerLinks creates a ReplacerLinks backed by an already-indexed
+// LinkResolver.
+func NewReplacerLinks(resolver *LinkResolver) *ReplacerLinks {
+	return &ReplacerLinks{resolver: resolver}
+}
+
+// Match reports whether a tag is a link that this replacer knows how to
+// rewrite, i.e. an <a> tag whose href is an evernote:///view/... link.
+func (r *ReplacerLinks) Match(tag string) bool {
+	return strings.HasPrefix(tag, "<a ") && strings.Contains(tag, "evernote:///view/")
+}
+
+// Replace rewrites the matched anchor tag's href to the resolved link
+// destination, leaving the tag untouched when the target cannot be resolved.
+func (r *ReplacerLinks) Replace(tag string) string {
+	m := hrefPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return tag
+	}
+
+	resolved, ok := r.resolver.ResolveLink(m[1])
+	if !ok {
+		return tag
+	}
+
+	return hrefPattern.ReplaceAllString(tag, fmt.Sprintf(`href="%s"`, resolved))
+}
+
+var hrefPattern = regexp.MustCompile(`href="([^"]+)"`)
+
+// wikiTitlePattern matches a bare in-note reference by title, written as
+// "[[Some Note Title]]" in the note body. This is the convention used for
+// referencing a note by title rather than by its evernote:///view/... URL,
+// and is the only path that exercises LinkResolver.ResolveTitle's exact- and
+// fuzzy-title matching.
+var wikiTitlePattern = regexp.MustCompile(`\[\[([^\]\[]+)\]\]`)
+
+// resolveTitleLinks rewrites "[[Title]]" in-note references in md.Content to
+// the link rendered by ResolveTitle, leaving unresolvable references untouched.
+// It runs on the Markdown content after toMarkdown, rather than as a
+// normalizeHTML Replacer, since a title reference is plain text rather than
+// an HTML tag.
+func (c *Converter) resolveTitleLinks(_ *enex.Note, md *markdown.Note) {
+	if c.err != nil || c.LinkResolver == nil {
+		return
+	}
+
+	md.Content = wikiTitlePattern.ReplaceAllFunc(md.Content, func(match []byte) []byte {
+		title := string(wikiTitlePattern.FindSubmatch(match)[1])
+		resolved, ok := c.LinkResolver.ResolveTitle(title)
+		if !ok {
+			return match
+		}
+
+		return []byte(resolved)
+	})
+}
@@ -0,0 +1,72 @@
+package internal
+
+import "testing"
+
+func TestLinkResolver_ResolveLink(t *testing.T) {
+	r := NewLinkResolver(LinkFormatMarkdown)
+	r.Index(NoteRef{GUID: "abc-123", Title: "Code Review", RelPath: "code-review.md"})
+	r.Index(NoteRef{GUID: "def-456", Title: "Overview", RelPath: "overview.md"})
+
+	t.Run("resolves by GUID", func(t *testing.T) {
+		resolved, ok := r.ResolveLink("evernote:///view/1/s1/abc-123/abc-123/")
+		if !ok || resolved != "[Code Review](code-review.md)" {
+			t.Fatalf("got (%q, %v), want ([Code Review](code-review.md), true)", resolved, ok)
+		}
+	})
+
+	t.Run("does not fuzzy-match an unresolved URL against coincidental title substrings", func(t *testing.T) {
+		// Every evernote:///view/... URL literally contains "view", which is
+		// also a substring of the indexed "Overview" and "Code Review"
+		// titles. A link to a GUID that isn't indexed must stay unresolved,
+		// not silently attach itself to one of those notes.
+		_, ok := r.ResolveLink("evernote:///view/1/s1/zzz-999/zzz-999/")
+		if ok {
+			t.Fatal("expected an unindexed GUID to be unresolved, got a match")
+		}
+	})
+
+	t.Run("records unresolved targets", func(t *testing.T) {
+		r := NewLinkResolver(LinkFormatMarkdown)
+		target := "evernote:///view/1/s1/missing/missing/"
+		if _, ok := r.ResolveLink(target); ok {
+			t.Fatal("expected no match")
+		}
+		if len(r.Unresolved) != 1 || r.Unresolved[0] != target {
+			t.Fatalf("got Unresolved=%v, want [%q]", r.Unresolved, target)
+		}
+	})
+}
+
+func TestLinkResolver_ResolveTitle(t *testing.T) {
+	t.Run("exact title match", func(t *testing.T) {
+		r := NewLinkResolver(LinkFormatMarkdown)
+		r.Index(NoteRef{Title: "Grocery List", RelPath: "grocery-list.md"})
+
+		resolved, ok := r.ResolveTitle("Grocery List")
+		if !ok || resolved != "[Grocery List](grocery-list.md)" {
+			t.Fatalf("got (%q, %v)", resolved, ok)
+		}
+	})
+
+	t.Run("unambiguous fuzzy match", func(t *testing.T) {
+		r := NewLinkResolver(LinkFormatMarkdown)
+		r.Index(NoteRef{Title: "2024 Grocery List (final)", RelPath: "grocery.md"})
+
+		resolved, ok := r.ResolveTitle("Grocery List")
+		if !ok || resolved != "[2024 Grocery List (final)](grocery.md)" {
+			t.Fatalf("got (%q, %v)", resolved, ok)
+		}
+	})
+
+	t.Run("ambiguous fuzzy match is unresolved, deterministically", func(t *testing.T) {
+		r := NewLinkResolver(LinkFormatMarkdown)
+		r.Index(NoteRef{Title: "Code Review", RelPath: "code-review.md"})
+		r.Index(NoteRef{Title: "Overview", RelPath: "overview.md"})
+
+		for i := 0; i < 20; i++ {
+			if _, ok := r.ResolveTitle("view"); ok {
+				t.Fatal("expected an ambiguous substring match to stay unresolved")
+			}
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+
+	"github.com/wormi4ok/evernote2md/encoding/markdown"
+)
+
+// MarkdownFlavor selects which Renderer Converter.toMarkdown uses.
+type MarkdownFlavor string
+
+const (
+	// MarkdownCommonMark renders strict CommonMark: task list items and
+	// highlights both degrade to plain text, since CommonMark has no task
+	// list or highlight syntax.
+	MarkdownCommonMark MarkdownFlavor = "commonmark"
+	// MarkdownGFM renders GitHub-Flavored Markdown: "- [ ]" task lists,
+	// tables, strikethrough and autolinks.
+	MarkdownGFM MarkdownFlavor = "gfm"
+	// MarkdownBlackfriday renders a profile compatible with the legacy
+	// Blackfriday-based static site generators, which predate GFM task
+	// lists and the ==highlight== extension: task list items degrade to
+	// plain "[ ]"/"[x]" text and highlights become "<mark>...</mark>".
+	MarkdownBlackfriday MarkdownFlavor = "blackfriday"
+)
+
+// Renderer converts Evernote's note HTML (ENML) into a specific Markdown
+// dialect. Implementations decide how Evernote-specific markup - <en-todo>
+// checkboxes and highlights - map onto that dialect's syntax: every Renderer
+// below runs the same markdown.Convert, and those whose dialect differs from
+// its output (CommonMarkRenderer, BlackfridayRenderer) then post-process it
+// (task-list syntax, highlight syntax) to match, since
+// encoding/markdown itself only takes the two EnableHighlights/
+// EscapeSpecialChars flags.
+//
+// Inferring a fenced code block's language from Evernote's -en-codeblock
+// style hints is out of scope here: that needs access to the source HTML
+// before markdown.Convert discards it, which none of these Renderers do.
+type Renderer interface {
+	// Render converts the HTML read from r into Markdown written to w.
+	Render(w io.Writer, r io.Reader) error
+}
+
+// taskListPattern matches a GFM task list item ("- [ ] " / "- [x] ") at the
+// start of a line, as emitted by markdown.Convert. CommonMarkRenderer and
+// BlackfridayRenderer use it to flatten task lists to plain "[ ]"/"[x]" text,
+// since neither dialect supports GFM task list syntax.
+var taskListPattern = regexp.MustCompile(`(?m)^(\s*)-\s\[([ xX])\]\s+`)
+
+func flattenTaskLists(content []byte) []byte {
+	return taskListPattern.ReplaceAll(content, []byte("$1[$2] "))
+}
+
+// rendererFor resolves the Renderer to use for the Converter's configured
+// MarkdownFlavor, defaulting to CommonMarkRenderer for the zero value.
+func (c *Converter) rendererFor() Renderer {
+	switch c.MarkdownFlavor {
+	case MarkdownGFM:
+		return &GFMRenderer{EnableHighlights: c.EnableHighlights, EscapeSpecialChars: c.EscapeSpecialChars}
+	case MarkdownBlackfriday:
+		return &BlackfridayRenderer{EscapeSpecialChars: c.EscapeSpecialChars}
+	case MarkdownCommonMark:
+		fallthrough
+	default:
+		return &CommonMarkRenderer{EscapeSpecialChars: c.EscapeSpecialChars}
+	}
+}
+
+// CommonMarkRenderer renders strict CommonMark. Evernote highlights are
+// dropped to plain text and <en-todo> checkboxes become plain "[ ]"/"[x]"
+// text rather than a GFM task list item, since neither has a CommonMark
+// equivalent.
+type CommonMarkRenderer struct {
+	EscapeSpecialChars bool
+}
+
+// Render implements Renderer.
+func (r *CommonMarkRenderer) Render(w io.Writer, src io.Reader) error {
+	var in, out bytes.Buffer
+	if _, err := io.Copy(&in, src); err != nil {
+		return err
+	}
+
+	if err := markdown.Convert(&out, bytes.NewReader(in.Bytes()), false, r.EscapeSpecialChars); err != nil {
+		return err
+	}
+
+	_, err := w.Write(flattenTaskLists(out.Bytes()))
+	return err
+}
+
+// GFMRenderer renders GitHub-Flavored Markdown as markdown.Convert already
+// produces it: <en-todo> as a "- [ ]"/"- [x]" task list item, and highlights
+// as "==highlight==" when EnableHighlights is set.
+type GFMRenderer struct {
+	EnableHighlights   bool
+	EscapeSpecialChars bool
+}
+
+// Render implements Renderer.
+func (r *GFMRenderer) Render(w io.Writer, src io.Reader) error {
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, src); err != nil {
+		return err
+	}
+
+	return markdown.Convert(w, bytes.NewReader(b.Bytes()), r.EnableHighlights, r.EscapeSpecialChars)
+}
+
+// BlackfridayRenderer renders a profile compatible with legacy
+// Blackfriday-based static site generators: GFM task list items are
+// flattened to plain "[ ]"/"[x]" text, and highlights are rendered as
+// "<mark>...</mark>" rather than "==highlight==", since Blackfriday predates
+// both the GFM task list and ==highlight== extensions.
+type BlackfridayRenderer struct {
+	EscapeSpecialChars bool
+}
+
+// blackfridayHighlightPattern matches the "==highlight==" syntax that
+// markdown.Convert emits when asked for highlights, so Render can translate
+// it to the "<mark>...</mark>" HTML Blackfriday understands instead.
+var blackfridayHighlightPattern = regexp.MustCompile(`==(.+?)==`)
+
+// Render implements Renderer.
+func (r *BlackfridayRenderer) Render(w io.Writer, src io.Reader) error {
+	var in, out bytes.Buffer
+	if _, err := io.Copy(&in, src); err != nil {
+		return err
+	}
+
+	if err := markdown.Convert(&out, bytes.NewReader(in.Bytes()), true, r.EscapeSpecialChars); err != nil {
+		return err
+	}
+
+	rewritten := blackfridayHighlightPattern.ReplaceAll(out.Bytes(), []byte("<mark>$1</mark>"))
+	rewritten = flattenTaskLists(rewritten)
+	_, err := w.Write(rewritten)
+
+	return err
+}
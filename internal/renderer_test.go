@@ -0,0 +1,48 @@
+package internal
+
+import "testing"
+
+func TestFlattenTaskLists(t *testing.T) {
+	cases := map[string]string{
+		"- [ ] buy milk":       "[ ] buy milk",
+		"- [x] done already":   "[x] done already",
+		"  - [X] indented":     "  [X] indented",
+		"not a task list line": "not a task list line",
+	}
+
+	for in, want := range cases {
+		if got := string(flattenTaskLists([]byte(in))); got != want {
+			t.Errorf("flattenTaskLists(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConverter_rendererFor(t *testing.T) {
+	cases := map[MarkdownFlavor]Renderer{
+		MarkdownGFM:         &GFMRenderer{},
+		MarkdownBlackfriday: &BlackfridayRenderer{},
+		MarkdownCommonMark:  &CommonMarkRenderer{},
+		"":                  &CommonMarkRenderer{},
+	}
+
+	for flavor, want := range cases {
+		c := &Converter{MarkdownFlavor: flavor}
+		got := c.rendererFor()
+		if gotType, wantType := typeName(got), typeName(want); gotType != wantType {
+			t.Errorf("rendererFor() for flavor %q = %s, want %s", flavor, gotType, wantType)
+		}
+	}
+}
+
+func typeName(r Renderer) string {
+	switch r.(type) {
+	case *GFMRenderer:
+		return "GFMRenderer"
+	case *BlackfridayRenderer:
+		return "BlackfridayRenderer"
+	case *CommonMarkRenderer:
+		return "CommonMarkRenderer"
+	default:
+		return "unknown"
+	}
+}